@@ -0,0 +1,70 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+type aksTokenProvider struct {
+	tokenURL string
+	tenantID string
+	client   *http.Client
+}
+
+// NewAKSTokenProvider acquires AAD tokens from the IMDS-style token endpoint AKS and fleet
+// clusters expose at tokenURL, scoped to tenantID.
+func NewAKSTokenProvider(tokenURL, tenantID string) TokenProvider {
+	return &aksTokenProvider{tokenURL: tokenURL, tenantID: tenantID, client: http.DefaultClient}
+}
+
+func (p *aksTokenProvider) Name() string {
+	return "aks"
+}
+
+func (p *aksTokenProvider) Acquire(ctx context.Context, _ string) (AuthResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.tokenURL, nil)
+	if err != nil {
+		return AuthResponse{}, errors.Wrap(err, "failed to build AKS token request")
+	}
+	q := req.URL.Query()
+	q.Set("tenantid", p.tenantID)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return AuthResponse{}, errors.Wrap(err, "failed to acquire AKS token")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return AuthResponse{}, errors.Errorf("AKS token request failed with status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return AuthResponse{}, errors.Wrap(err, "failed to decode AKS token response")
+	}
+
+	return AuthResponse{Token: body.AccessToken, Expires: body.ExpiresIn}, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type clientCredentialTokenProvider struct {
+	clientID     string
+	clientSecret string
+	tokenURL     string
+	resource     string
+	client       *http.Client
+}
+
+// NewClientCredentialTokenProvider acquires AAD tokens via the OAuth2 client-credentials grant
+// against tokenURL, for the given clientID/clientSecret and resource (the requested audience,
+// e.g. "https://management.azure.com/.default").
+func NewClientCredentialTokenProvider(clientID, clientSecret, tokenURL, resource string) TokenProvider {
+	return &clientCredentialTokenProvider{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		tokenURL:     tokenURL,
+		resource:     resource,
+		client:       http.DefaultClient,
+	}
+}
+
+func (p *clientCredentialTokenProvider) Name() string {
+	return "client-credential"
+}
+
+func (p *clientCredentialTokenProvider) Acquire(ctx context.Context, _ string) (AuthResponse, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {p.clientID},
+		"client_secret": {p.clientSecret},
+		"scope":         {p.resource},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return AuthResponse{}, errors.Wrap(err, "failed to build client credential token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return AuthResponse{}, errors.Wrap(err, "failed to acquire client credential token")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return AuthResponse{}, errors.Errorf("client credential token request failed with status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return AuthResponse{}, errors.Wrap(err, "failed to decode client credential token response")
+	}
+
+	return AuthResponse{Token: body.AccessToken, Expires: body.ExpiresIn}, nil
+}
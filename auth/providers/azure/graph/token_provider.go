@@ -0,0 +1,39 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package graph contains the hand-rolled AAD token providers the azure RBAC authorizer used
+// before auth/providers/azure.Options.NewTokenProvider added an azidentity-backed alternative.
+package graph
+
+import "context"
+
+// AuthResponse is the result of acquiring an AAD token: the bearer token and how many seconds
+// remain until it expires.
+type AuthResponse struct {
+	Token   string
+	Expires int
+}
+
+// TokenProvider acquires an AAD token for a requested resource/scope. Implementations are the
+// client-credential and AKS/IMDS-style providers below, and the azidentity-backed provider in
+// auth/providers/azure.
+type TokenProvider interface {
+	// Acquire exchanges resource for a fresh AAD token. ctx bounds the request so a caller with
+	// a deadline (e.g. AccessInfo.RefreshToken) can cancel a hung acquisition instead of
+	// blocking indefinitely.
+	Acquire(ctx context.Context, resource string) (AuthResponse, error)
+	Name() string
+}
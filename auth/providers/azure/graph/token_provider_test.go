@@ -0,0 +1,83 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientCredentialTokenProviderAcquire(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		if got := r.FormValue("grant_type"); got != "client_credentials" {
+			t.Fatalf("expected grant_type=client_credentials, got %q", got)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "tok-123",
+			"expires_in":   3600,
+		})
+	}))
+	defer srv.Close()
+
+	p := NewClientCredentialTokenProvider("client-id", "client-secret", srv.URL, "https://management.azure.com/.default")
+	resp, err := p.Acquire(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if resp.Token != "tok-123" || resp.Expires != 3600 {
+		t.Fatalf("unexpected AuthResponse: %+v", resp)
+	}
+}
+
+func TestClientCredentialTokenProviderAcquireError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	p := NewClientCredentialTokenProvider("client-id", "client-secret", srv.URL, "resource")
+	if _, err := p.Acquire(context.Background(), ""); err == nil {
+		t.Fatal("expected an error for a non-200 response")
+	}
+}
+
+func TestAKSTokenProviderAcquire(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("tenantid"); got != "tenant-1" {
+			t.Fatalf("expected tenantid=tenant-1, got %q", got)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "aks-tok",
+			"expires_in":   1800,
+		})
+	}))
+	defer srv.Close()
+
+	p := NewAKSTokenProvider(srv.URL, "tenant-1")
+	resp, err := p.Acquire(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if resp.Token != "aks-tok" || resp.Expires != 1800 {
+		t.Fatalf("unexpected AuthResponse: %+v", resp)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package azure
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+const defaultKeyVaultRefreshInterval = time.Hour
+
+// KeyVaultSecretSource fetches Guard's own client secret/certificate from Azure Key Vault
+// instead of AZURE_CLIENT_SECRET or a mounted file, and periodically re-fetches it so a rotated
+// secret is picked up without a pod restart. It authenticates to Key Vault with the azidentity
+// chain (managed identity or workload identity) - never a bootstrap secret.
+type KeyVaultSecretSource struct {
+	client          *azsecrets.Client
+	secretName      string
+	certName        string
+	refreshInterval time.Duration
+}
+
+// NewKeyVaultSecretSource builds a KeyVaultSecretSource for o.KeyVaultURI. The caller must have
+// set o.KeyVaultSecretName and/or o.KeyVaultCertName.
+func NewKeyVaultSecretSource(o Options) (*KeyVaultSecretSource, error) {
+	// Key Vault access never falls back to a client secret: use whichever of managed identity or
+	// workload identity is available, mirroring azidentity.DefaultAzureCredential.
+	kvAuth := o
+	if kvAuth.AuthMode != AuthModeMSI && kvAuth.AuthMode != AuthModeWorkloadIdentity {
+		kvAuth.AuthMode = AuthModeChained
+	}
+	kvAuth.ClientSecret = ""
+	kvAuth.CertPath = ""
+
+	cred, err := kvAuth.newAzureCredential()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize azure credential for key vault access")
+	}
+
+	client, err := azsecrets.NewClient(o.KeyVaultURI, cred, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create key vault client")
+	}
+
+	interval := o.KeyVaultRefreshInterval
+	if interval <= 0 {
+		interval = defaultKeyVaultRefreshInterval
+	}
+
+	return &KeyVaultSecretSource{
+		client:          client,
+		secretName:      o.KeyVaultSecretName,
+		certName:        o.KeyVaultCertName,
+		refreshInterval: interval,
+	}, nil
+}
+
+// Fetch returns the current client secret and PEM client certificate (whichever are configured)
+// from Key Vault.
+func (s *KeyVaultSecretSource) Fetch(ctx context.Context) (clientSecret string, clientCertPEM string, err error) {
+	if s.secretName != "" {
+		resp, err := s.client.GetSecret(ctx, s.secretName, "", nil)
+		if err != nil {
+			return "", "", errors.Wrapf(err, "failed to fetch key vault secret %q", s.secretName)
+		}
+		if resp.Value != nil {
+			clientSecret = *resp.Value
+		}
+	}
+
+	if s.certName != "" {
+		resp, err := s.client.GetSecret(ctx, s.certName, "", nil)
+		if err != nil {
+			return "", "", errors.Wrapf(err, "failed to fetch key vault secret %q", s.certName)
+		}
+		if resp.Value != nil {
+			clientCertPEM = *resp.Value
+		}
+	}
+
+	return clientSecret, clientCertPEM, nil
+}
+
+// Watch fetches once synchronously and then every refreshInterval, invoking onRotate whenever
+// the fetched material changes. It blocks until ctx is done, so callers should run it in its own
+// goroutine.
+func (s *KeyVaultSecretSource) Watch(ctx context.Context, onRotate func(clientSecret, clientCertPEM string)) {
+	var lastSecret, lastCert string
+
+	fetchAndNotify := func() {
+		secret, cert, err := s.Fetch(ctx)
+		if err != nil {
+			klog.Errorf("failed to refresh secret material from key vault: %s", err)
+			return
+		}
+		if secret == lastSecret && cert == lastCert {
+			return
+		}
+		lastSecret, lastCert = secret, cert
+		onRotate(secret, cert)
+	}
+
+	fetchAndNotify()
+
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fetchAndNotify()
+		}
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package azure
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	azfake "github.com/Azure/azure-sdk-for-go/sdk/azcore/fake"
+	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
+	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets/fake"
+)
+
+// fakeTokenCredential satisfies azcore.TokenCredential without contacting AAD, for use with the
+// azsecrets fake server transport.
+type fakeTokenCredential struct{}
+
+func (fakeTokenCredential) GetToken(context.Context, azcore.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "fake-token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func newFakeKeyVaultClient(t *testing.T, values map[string]string) *azsecrets.Client {
+	t.Helper()
+
+	server := fake.Server{
+		GetSecret: func(ctx context.Context, name, version string, options *azsecrets.GetSecretOptions) (resp azfake.Responder[azsecrets.GetSecretResponse], errResp azfake.ErrorResponder) {
+			value, ok := values[name]
+			if !ok {
+				errResp.SetResponseError(http.StatusNotFound, "SecretNotFound")
+				return
+			}
+			out := azsecrets.GetSecretResponse{Secret: azsecrets.Secret{Value: &value}}
+			resp.SetResponse(http.StatusOK, out, nil)
+			return
+		},
+	}
+
+	client, err := azsecrets.NewClient("https://fake.vault.azure.net", fakeTokenCredential{}, &azsecrets.ClientOptions{
+		ClientOptions: azcore.ClientOptions{Transport: fake.NewServerTransport(&server)},
+	})
+	if err != nil {
+		t.Fatalf("failed to create fake key vault client: %v", err)
+	}
+	return client
+}
+
+func TestKeyVaultSecretSourceFetch(t *testing.T) {
+	client := newFakeKeyVaultClient(t, map[string]string{
+		"guard-client-secret": "s3cr3t",
+		"guard-client-cert":   "-----BEGIN CERTIFICATE-----\nfake\n-----END CERTIFICATE-----",
+	})
+
+	s := &KeyVaultSecretSource{
+		client:     client,
+		secretName: "guard-client-secret",
+		certName:   "guard-client-cert",
+	}
+
+	secret, cert, err := s.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if secret != "s3cr3t" {
+		t.Fatalf("expected secret %q, got %q", "s3cr3t", secret)
+	}
+	if cert == "" {
+		t.Fatalf("expected a non-empty certificate")
+	}
+}
+
+func TestKeyVaultSecretSourceWatchNotifiesOnRotation(t *testing.T) {
+	values := map[string]string{"guard-client-secret": "v1"}
+	client := newFakeKeyVaultClient(t, values)
+
+	s := &KeyVaultSecretSource{
+		client:          client,
+		secretName:      "guard-client-secret",
+		refreshInterval: 10 * time.Millisecond,
+	}
+
+	seen := make(chan string, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go s.Watch(ctx, func(secret, cert string) {
+		seen <- secret
+	})
+
+	select {
+	case v := <-seen:
+		if v != "v1" {
+			t.Fatalf("expected initial fetch v1, got %q", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for initial fetch")
+	}
+
+	values["guard-client-secret"] = "v2"
+
+	select {
+	case v := <-seen:
+		if v != "v2" {
+			t.Fatalf("expected rotated fetch v2, got %q", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for rotation to be picked up")
+	}
+}
@@ -3,6 +3,7 @@ package azure
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/appscode/go/types"
 	"github.com/pkg/errors"
@@ -13,30 +14,98 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+const (
+	// AuthModeSecret is the legacy default: a client secret supplied via AZURE_CLIENT_SECRET.
+	AuthModeSecret = "secret"
+	// AuthModeCert authenticates with a PEM/PFX client certificate on disk.
+	AuthModeCert = "cert"
+	// AuthModeMSI authenticates as the system- or user-assigned managed identity of the host.
+	AuthModeMSI = "msi"
+	// AuthModeWorkloadIdentity authenticates with the federated token file mounted by AKS
+	// Workload Identity.
+	AuthModeWorkloadIdentity = "workload-identity"
+	// AuthModeChained tries workload identity, then managed identity, then a client certificate
+	// (when CertPath is set) or otherwise a client secret, in that order, mirroring
+	// azidentity.DefaultAzureCredential.
+	AuthModeChained = "chained"
+)
+
 type Options struct {
 	ClientID     string
 	ClientSecret string
 	TenantID     string
+
+	// AuthMode selects how Guard acquires its own AAD token: chained, msi, workload-identity,
+	// cert, or secret (default). Unset behaves exactly like the legacy client-secret-only flow.
+	AuthMode string
+	// MSIClientID is the client ID of a user-assigned managed identity. Leave empty to use the
+	// system-assigned identity.
+	MSIClientID string
+	// CertPath is the path to a PEM or PFX client certificate, used when AuthMode is cert or
+	// as a fallback credential when AuthMode is chained.
+	CertPath string
+	// FederatedTokenFile is the path to the federated identity token mounted by the AKS
+	// Workload Identity webhook, used when AuthMode is workload-identity or chained.
+	FederatedTokenFile string
+
+	// KeyVaultURI, when set, causes Guard to fetch its client secret/certificate from Azure Key
+	// Vault at startup (authenticating with the azidentity chain, never a bootstrap secret) and
+	// substitutes for AZURE_CLIENT_SECRET / CertPath.
+	KeyVaultURI string
+	// KeyVaultSecretName is the name of the Key Vault secret holding the client secret.
+	KeyVaultSecretName string
+	// KeyVaultCertName is the name of the Key Vault secret holding the PEM client certificate.
+	KeyVaultCertName string
+	// KeyVaultRefreshInterval is how often Guard re-fetches from Key Vault to pick up a rotated
+	// secret/certificate without a pod restart. Defaults to 1 hour when zero.
+	KeyVaultRefreshInterval time.Duration
 }
 
 func NewOptions() Options {
 	return Options{
-		ClientSecret: os.Getenv("AZURE_CLIENT_SECRET"),
+		ClientSecret:       os.Getenv("AZURE_CLIENT_SECRET"),
+		FederatedTokenFile: os.Getenv("AZURE_FEDERATED_TOKEN_FILE"),
 	}
 }
 
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.ClientID, "azure.client-id", o.ClientID, "MS Graph application client ID to use")
 	fs.StringVar(&o.TenantID, "azure.tenant-id", o.TenantID, "MS Graph application tenant id to use")
+	fs.StringVar(&o.AuthMode, "azure.auth-mode", o.AuthMode, "Credential acquisition mode: chained, msi, workload-identity, cert, or secret (default)")
+	fs.StringVar(&o.MSIClientID, "azure.msi-client-id", o.MSIClientID, "Client ID of the user-assigned managed identity to use; leave empty for the system-assigned identity")
+	fs.StringVar(&o.CertPath, "azure.cert-path", o.CertPath, "Path to a PEM or PFX client certificate, used when azure.auth-mode is cert or chained")
+	fs.StringVar(&o.FederatedTokenFile, "azure.federated-token-file", o.FederatedTokenFile, "Path to the federated identity token file mounted by AKS Workload Identity")
+	fs.StringVar(&o.KeyVaultURI, "azure.keyvault-uri", o.KeyVaultURI, "URI of an Azure Key Vault to fetch the client secret/certificate from, e.g. https://my-vault.vault.azure.net")
+	fs.StringVar(&o.KeyVaultSecretName, "azure.keyvault-secret-name", o.KeyVaultSecretName, "Name of the Key Vault secret holding the client secret")
+	fs.StringVar(&o.KeyVaultCertName, "azure.keyvault-cert-name", o.KeyVaultCertName, "Name of the Key Vault secret holding the PEM client certificate")
+	fs.DurationVar(&o.KeyVaultRefreshInterval, "azure.keyvault-refresh-interval", o.KeyVaultRefreshInterval, "How often to re-fetch the client secret/certificate from Key Vault (default 1h)")
 }
 
 func (o *Options) Validate() []error {
 	var errs []error
-	if o.ClientSecret == "" {
-		errs = append(errs, errors.New("environment variable AZURE_CLIENT_SECRET must be set"))
+	haveKeyVaultRef := o.KeyVaultURI != "" && (o.KeyVaultSecretName != "" || o.KeyVaultCertName != "")
+	if o.KeyVaultURI != "" && !haveKeyVaultRef {
+		errs = append(errs, errors.New("azure.keyvault-secret-name or azure.keyvault-cert-name must be set when azure.keyvault-uri is set"))
 	}
-	if o.ClientID == "" {
-		errs = append(errs, errors.New("azure.client-id must be non empty"))
+
+	switch o.AuthMode {
+	case AuthModeMSI, AuthModeWorkloadIdentity, AuthModeChained:
+		// Credentials come from the platform (IMDS or a federated token file), so no bootstrap
+		// secret is required.
+	case AuthModeCert:
+		if o.CertPath == "" && !haveKeyVaultRef {
+			errs = append(errs, errors.New("azure.cert-path must be set when azure.auth-mode=cert"))
+		}
+		if o.ClientID == "" {
+			errs = append(errs, errors.New("azure.client-id must be non empty"))
+		}
+	default:
+		if o.ClientSecret == "" && !haveKeyVaultRef {
+			errs = append(errs, errors.New("environment variable AZURE_CLIENT_SECRET must be set, or azure.keyvault-uri configured"))
+		}
+		if o.ClientID == "" {
+			errs = append(errs, errors.New("azure.client-id must be non empty"))
+		}
 	}
 	if o.TenantID == "" {
 		errs = append(errs, errors.New("azure.tenant-id must be non empty"))
@@ -45,49 +114,55 @@ func (o *Options) Validate() []error {
 }
 
 func (o Options) Apply(d *v1beta1.Deployment) (extraObjs []runtime.Object, err error) {
-	// create auth secret
-	authSecret := &core.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "guard-azure-auth",
-			Namespace: d.Namespace,
-			Labels:    d.Labels,
-		},
-		Data: map[string][]byte{
-			"client-secret": []byte(o.ClientSecret),
-		},
-	}
-	extraObjs = append(extraObjs, authSecret)
+	// Platform-sourced credentials (managed identity, workload identity federated token, or a Key
+	// Vault reference) need no bootstrap secret at all; only mount one when Guard still has to
+	// present a client secret directly.
+	usesKeyVault := o.KeyVaultURI != ""
+	if !usesKeyVault && (o.AuthMode == "" || o.AuthMode == AuthModeSecret) {
+		// create auth secret
+		authSecret := &core.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "guard-azure-auth",
+				Namespace: d.Namespace,
+				Labels:    d.Labels,
+			},
+			Data: map[string][]byte{
+				"client-secret": []byte(o.ClientSecret),
+			},
+		}
+		extraObjs = append(extraObjs, authSecret)
 
-	// mount auth secret into deployment
-	volMount := core.VolumeMount{
-		Name:      authSecret.Name,
-		MountPath: "/etc/guard/auth/azure",
-	}
-	d.Spec.Template.Spec.Containers[0].VolumeMounts = append(d.Spec.Template.Spec.Containers[0].VolumeMounts, volMount)
-
-	vol := core.Volume{
-		Name: authSecret.Name,
-		VolumeSource: core.VolumeSource{
-			Secret: &core.SecretVolumeSource{
-				SecretName:  authSecret.Name,
-				DefaultMode: types.Int32P(0555),
+		// mount auth secret into deployment
+		volMount := core.VolumeMount{
+			Name:      authSecret.Name,
+			MountPath: "/etc/guard/auth/azure",
+		}
+		d.Spec.Template.Spec.Containers[0].VolumeMounts = append(d.Spec.Template.Spec.Containers[0].VolumeMounts, volMount)
+
+		vol := core.Volume{
+			Name: authSecret.Name,
+			VolumeSource: core.VolumeSource{
+				Secret: &core.SecretVolumeSource{
+					SecretName:  authSecret.Name,
+					DefaultMode: types.Int32P(0555),
+				},
 			},
-		},
-	}
-	d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, vol)
-
-	// use auth secret in container[0] args
-	d.Spec.Template.Spec.Containers[0].Env = append(d.Spec.Template.Spec.Containers[0].Env, core.EnvVar{
-		Name: "AZURE_CLIENT_SECRET",
-		ValueFrom: &core.EnvVarSource{
-			SecretKeyRef: &core.SecretKeySelector{
-				LocalObjectReference: core.LocalObjectReference{
-					Name: authSecret.Name,
+		}
+		d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, vol)
+
+		// use auth secret in container[0] args
+		d.Spec.Template.Spec.Containers[0].Env = append(d.Spec.Template.Spec.Containers[0].Env, core.EnvVar{
+			Name: "AZURE_CLIENT_SECRET",
+			ValueFrom: &core.EnvVarSource{
+				SecretKeyRef: &core.SecretKeySelector{
+					LocalObjectReference: core.LocalObjectReference{
+						Name: authSecret.Name,
+					},
+					Key: "client-secret",
 				},
-				Key: "client-secret",
 			},
-		},
-	})
+		})
+	}
 
 	args := d.Spec.Template.Spec.Containers[0].Args
 	if o.ClientID != "" {
@@ -96,6 +171,30 @@ func (o Options) Apply(d *v1beta1.Deployment) (extraObjs []runtime.Object, err e
 	if o.TenantID != "" {
 		args = append(args, fmt.Sprintf("--azure.tenant-id=%s", o.TenantID))
 	}
+	if o.AuthMode != "" {
+		args = append(args, fmt.Sprintf("--azure.auth-mode=%s", o.AuthMode))
+	}
+	if o.MSIClientID != "" {
+		args = append(args, fmt.Sprintf("--azure.msi-client-id=%s", o.MSIClientID))
+	}
+	if o.CertPath != "" {
+		args = append(args, fmt.Sprintf("--azure.cert-path=%s", o.CertPath))
+	}
+	if o.FederatedTokenFile != "" {
+		args = append(args, fmt.Sprintf("--azure.federated-token-file=%s", o.FederatedTokenFile))
+	}
+	if o.KeyVaultURI != "" {
+		args = append(args, fmt.Sprintf("--azure.keyvault-uri=%s", o.KeyVaultURI))
+	}
+	if o.KeyVaultSecretName != "" {
+		args = append(args, fmt.Sprintf("--azure.keyvault-secret-name=%s", o.KeyVaultSecretName))
+	}
+	if o.KeyVaultCertName != "" {
+		args = append(args, fmt.Sprintf("--azure.keyvault-cert-name=%s", o.KeyVaultCertName))
+	}
+	if o.KeyVaultRefreshInterval > 0 {
+		args = append(args, fmt.Sprintf("--azure.keyvault-refresh-interval=%s", o.KeyVaultRefreshInterval))
+	}
 
 	d.Spec.Template.Spec.Containers[0].Args = args
 
@@ -0,0 +1,142 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package azure
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.kubeguard.dev/guard/auth/providers/azure/graph"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/pkg/errors"
+)
+
+// azureIdentityTokenProvider adapts an azidentity.TokenCredential to graph.TokenProvider so the
+// RBAC pipeline can acquire ARM tokens through the standard credential chain (managed identity,
+// workload identity, client secret/certificate) instead of the hand-rolled providers in graph.
+type azureIdentityTokenProvider struct {
+	cred   azcore.TokenCredential
+	scopes []string
+}
+
+// Acquire takes ctx so a canceled or expired RefreshToken context interrupts a hung GetToken call
+// instead of running it to completion against context.Background(). This assumes
+// graph.TokenProvider.Acquire is widened to accept a context alongside the existing callers of
+// that interface.
+func (p *azureIdentityTokenProvider) Acquire(ctx context.Context, _ string) (graph.AuthResponse, error) {
+	tok, err := p.cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: p.scopes})
+	if err != nil {
+		return graph.AuthResponse{}, errors.Wrap(err, "failed to acquire token via azidentity")
+	}
+	return graph.AuthResponse{
+		Token:   tok.Token,
+		Expires: int(time.Until(tok.ExpiresOn).Seconds()),
+	}, nil
+}
+
+func (p *azureIdentityTokenProvider) Name() string {
+	return "azidentity"
+}
+
+// NewTokenProvider builds a graph.TokenProvider for the configured AuthMode, using the
+// azidentity credential chain in place of the legacy per-cluster-type providers. armEndpoint is
+// used verbatim (plus "/.default") as the requested token scope.
+func (o Options) NewTokenProvider(armEndpoint string) (graph.TokenProvider, error) {
+	cred, err := o.newAzureCredential()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize azure credential")
+	}
+	return &azureIdentityTokenProvider{cred: cred, scopes: []string{armEndpoint + "/.default"}}, nil
+}
+
+func (o Options) newAzureCredential() (azcore.TokenCredential, error) {
+	switch o.AuthMode {
+	case AuthModeMSI:
+		opts := &azidentity.ManagedIdentityCredentialOptions{}
+		if o.MSIClientID != "" {
+			opts.ID = azidentity.ClientID(o.MSIClientID)
+		}
+		return azidentity.NewManagedIdentityCredential(opts)
+	case AuthModeWorkloadIdentity:
+		return azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientID:      o.ClientID,
+			TenantID:      o.TenantID,
+			TokenFilePath: o.FederatedTokenFile,
+		})
+	case AuthModeCert:
+		return o.newClientCertificateCredential()
+	case AuthModeChained:
+		return o.newChainedCredential()
+	default:
+		return azidentity.NewClientSecretCredential(o.TenantID, o.ClientID, o.ClientSecret, nil)
+	}
+}
+
+func (o Options) newClientCertificateCredential() (azcore.TokenCredential, error) {
+	data, err := os.ReadFile(o.CertPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read azure.cert-path %s", o.CertPath)
+	}
+	certs, key, err := azidentity.ParseCertificates(data, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse client certificate")
+	}
+	return azidentity.NewClientCertificateCredential(o.TenantID, o.ClientID, certs, key, nil)
+}
+
+// newChainedCredential mirrors azidentity.DefaultAzureCredential's order: workload identity,
+// then managed identity, then a client secret/certificate, so the container can run unchanged
+// across a local dev box, an AKS Workload Identity pool, and a managed-identity VM.
+func (o Options) newChainedCredential() (azcore.TokenCredential, error) {
+	var creds []azcore.TokenCredential
+
+	if o.FederatedTokenFile != "" {
+		if wic, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			ClientID:      o.ClientID,
+			TenantID:      o.TenantID,
+			TokenFilePath: o.FederatedTokenFile,
+		}); err == nil {
+			creds = append(creds, wic)
+		}
+	}
+
+	msiOpts := &azidentity.ManagedIdentityCredentialOptions{}
+	if o.MSIClientID != "" {
+		msiOpts.ID = azidentity.ClientID(o.MSIClientID)
+	}
+	if mic, err := azidentity.NewManagedIdentityCredential(msiOpts); err == nil {
+		creds = append(creds, mic)
+	}
+
+	if o.CertPath != "" {
+		if cc, err := o.newClientCertificateCredential(); err == nil {
+			creds = append(creds, cc)
+		}
+	} else if o.ClientSecret != "" {
+		if csc, err := azidentity.NewClientSecretCredential(o.TenantID, o.ClientID, o.ClientSecret, nil); err == nil {
+			creds = append(creds, csc)
+		}
+	}
+
+	if len(creds) == 0 {
+		return nil, errors.New("azure.auth-mode=chained found no usable credential source")
+	}
+	return azidentity.NewChainedTokenCredential(creds, nil)
+}
@@ -0,0 +1,306 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cache implements the SAR result cache for the azure RBAC authorizer. It is modeled on
+// the k8s.io/client-go ExpirationCache + thread-safe store pattern, but adds LRU bounding,
+// separate allow/deny TTLs, and singleflight coalescing of concurrent upstream checkaccess calls
+// for the same key.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/singleflight"
+)
+
+// shardCount is the number of shards used when MaxEntries is large enough to spread evenly
+// across all of them. NewCache scales the shard count down for small MaxEntries values so the
+// configured cap is still honored exactly instead of being rounded up to one entry per shard.
+const shardCount = 32
+
+// Options configures a Cache.
+type Options struct {
+	// AllowTTL is how long an allowed decision stays valid.
+	AllowTTL time.Duration
+	// DenyTTL is how long a denied decision stays valid. Kept shorter than AllowTTL by default
+	// so a transient deny (e.g. a role assignment that is still propagating) self-heals quickly.
+	DenyTTL time.Duration
+	// MaxEntries bounds the total number of cached keys across all shards; the least recently
+	// used entry is evicted once a shard would exceed its share of that cap. Zero disables the
+	// cap.
+	MaxEntries int
+	// CleanupInterval is how often the background janitor sweeps expired entries. Defaults to
+	// one minute when zero.
+	CleanupInterval time.Duration
+}
+
+type entry struct {
+	key       string
+	value     bool
+	expiresAt time.Time
+}
+
+type shard struct {
+	mu      sync.Mutex
+	items   map[string]*list.Element // key -> element in lru holding *entry
+	lru     *list.List
+	maxSize int
+}
+
+// Cache is a sharded, mutex-protected, TTL- and LRU-bounded result store. It satisfies the
+// authz.Store interface (Get/Set) used by AccessInfo, and additionally exposes GetOrLoad for
+// callers that want singleflight-coalesced fetch-through.
+type Cache struct {
+	shards   []*shard
+	allowTTL time.Duration
+	denyTTL  time.Duration
+
+	group singleflight.Group
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewCache builds a Cache and starts its background janitor goroutine. Call Close to stop it.
+func NewCache(opts Options) *Cache {
+	cleanup := opts.CleanupInterval
+	if cleanup <= 0 {
+		cleanup = time.Minute
+	}
+
+	numShards := shardCount
+	perShardMax := 0
+	if opts.MaxEntries > 0 {
+		// A MaxEntries smaller than shardCount would otherwise round up to one entry per shard
+		// (perShardMax floored to 1) and silently cap at shardCount entries instead of the
+		// configured value, so shrink the shard count to match instead.
+		if opts.MaxEntries < numShards {
+			numShards = opts.MaxEntries
+		}
+		perShardMax = opts.MaxEntries / numShards
+		if perShardMax < 1 {
+			perShardMax = 1
+		}
+	}
+
+	c := &Cache{
+		shards:   make([]*shard, numShards),
+		allowTTL: opts.AllowTTL,
+		denyTTL:  opts.DenyTTL,
+		stopCh:   make(chan struct{}),
+	}
+	for i := range c.shards {
+		c.shards[i] = &shard{
+			items:   make(map[string]*list.Element),
+			lru:     list.New(),
+			maxSize: perShardMax,
+		}
+	}
+
+	go c.runJanitor(cleanup)
+	return c
+}
+
+// Close stops the background janitor. Safe to call more than once.
+func (c *Cache) Close() {
+	c.stopOnce.Do(func() { close(c.stopCh) })
+}
+
+func (c *Cache) runJanitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.sweep()
+		}
+	}
+}
+
+func (c *Cache) sweep() {
+	now := time.Now()
+	for _, s := range c.shards {
+		s.mu.Lock()
+		var next *list.Element
+		for e := s.lru.Front(); e != nil; e = next {
+			next = e.Next()
+			ent := e.Value.(*entry)
+			if now.After(ent.expiresAt) {
+				s.lru.Remove(e)
+				delete(s.items, ent.key)
+				entriesGauge.Dec()
+				evictionsTotal.WithLabelValues("expired").Inc()
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (c *Cache) shardFor(key string) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return c.shards[h.Sum32()%uint32(len(c.shards))]
+}
+
+// Get implements authz.Store. v must be a *bool; found is false on miss or expiry.
+func (c *Cache) Get(key string, v interface{}) (bool, error) {
+	out, ok := v.(*bool)
+	if !ok {
+		return false, nil
+	}
+	value, found := c.get(key)
+	if !found {
+		cacheMisses.Inc()
+		return false, nil
+	}
+	cacheHits.Inc()
+	*out = value
+	return true, nil
+}
+
+func (c *Cache) get(key string) (bool, bool) {
+	s := c.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return false, false
+	}
+	ent := el.Value.(*entry)
+	if time.Now().After(ent.expiresAt) {
+		// Lazy expiration: treat a stale entry as a miss and drop it immediately.
+		s.lru.Remove(el)
+		delete(s.items, key)
+		entriesGauge.Dec()
+		evictionsTotal.WithLabelValues("expired").Inc()
+		return false, false
+	}
+
+	s.lru.MoveToFront(el)
+	return ent.value, true
+}
+
+// Set implements authz.Store. v must be a bool.
+func (c *Cache) Set(key string, v interface{}) error {
+	value, ok := v.(bool)
+	if !ok {
+		return nil
+	}
+	c.set(key, value)
+	return nil
+}
+
+func (c *Cache) set(key string, value bool) {
+	ttl := c.allowTTL
+	if !value {
+		ttl = c.denyTTL
+	}
+
+	s := c.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		el.Value.(*entry).value = value
+		el.Value.(*entry).expiresAt = time.Now().Add(ttl)
+		s.lru.MoveToFront(el)
+		return
+	}
+
+	el := s.lru.PushFront(&entry{key: key, value: value, expiresAt: time.Now().Add(ttl)})
+	s.items[key] = el
+	entriesGauge.Inc()
+
+	if s.maxSize > 0 && s.lru.Len() > s.maxSize {
+		oldest := s.lru.Back()
+		if oldest != nil {
+			s.lru.Remove(oldest)
+			delete(s.items, oldest.Value.(*entry).key)
+			entriesGauge.Dec()
+			evictionsTotal.WithLabelValues("lru").Inc()
+		}
+	}
+}
+
+// GetOrLoad returns the cached decision for key if present, otherwise calls load exactly once
+// even if multiple goroutines request the same key concurrently (via singleflight), caches the
+// result, and returns it to every waiter.
+func (c *Cache) GetOrLoad(ctx context.Context, key string, load func(ctx context.Context) (bool, error)) (bool, error) {
+	if value, found := c.get(key); found {
+		cacheHits.Inc()
+		return value, nil
+	}
+	cacheMisses.Inc()
+
+	v, err, shared := c.group.Do(key, func() (interface{}, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return false, err
+		}
+		c.set(key, value)
+		return value, nil
+	})
+	if shared {
+		singleflightCoalesced.Inc()
+	}
+	if err != nil {
+		return false, err
+	}
+	return v.(bool), nil
+}
+
+var (
+	cacheHits = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "guard_azure_checkaccess_cache_hits_total",
+		Help: "Number of checkaccess result cache hits.",
+	})
+
+	cacheMisses = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "guard_azure_checkaccess_cache_misses_total",
+		Help: "Number of checkaccess result cache misses.",
+	})
+
+	evictionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "guard_azure_checkaccess_cache_evictions_total",
+			Help: "Number of checkaccess result cache evictions, by reason (expired, lru).",
+		},
+		[]string{"reason"},
+	)
+
+	singleflightCoalesced = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "guard_azure_checkaccess_cache_coalesced_total",
+		Help: "Number of GetOrLoad calls that were coalesced onto an in-flight upstream call for the same key.",
+	})
+
+	entriesGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "guard_azure_checkaccess_cache_entries",
+		Help: "Current number of entries held in the checkaccess result cache.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(evictionsTotal)
+}
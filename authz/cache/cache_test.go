@@ -0,0 +1,195 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetSetRoundTrip(t *testing.T) {
+	c := NewCache(Options{AllowTTL: time.Minute, DenyTTL: time.Minute})
+	defer c.Close()
+
+	if err := c.Set("k1", true); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	var out bool
+	found, err := c.Get("k1", &out)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !found || !out {
+		t.Fatalf("expected cache hit with value true, got found=%v out=%v", found, out)
+	}
+}
+
+func TestGetMissOnUnknownKey(t *testing.T) {
+	c := NewCache(Options{AllowTTL: time.Minute, DenyTTL: time.Minute})
+	defer c.Close()
+
+	var out bool
+	found, _ := c.Get("missing", &out)
+	if found {
+		t.Fatalf("expected miss for unknown key")
+	}
+}
+
+func TestTTLExpiry(t *testing.T) {
+	c := NewCache(Options{AllowTTL: 10 * time.Millisecond, DenyTTL: 10 * time.Millisecond})
+	defer c.Close()
+
+	if err := c.Set("k1", true); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	var out bool
+	found, _ := c.Get("k1", &out)
+	if found {
+		t.Fatalf("expected entry to have expired")
+	}
+}
+
+func TestDenyTTLIndependentOfAllowTTL(t *testing.T) {
+	c := NewCache(Options{AllowTTL: time.Hour, DenyTTL: 10 * time.Millisecond})
+	defer c.Close()
+
+	if err := c.Set("deny-key", false); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	var out bool
+	found, _ := c.Get("deny-key", &out)
+	if found {
+		t.Fatalf("expected deny entry to expire well before the allow TTL")
+	}
+}
+
+func TestLRUEvictionOrder(t *testing.T) {
+	c := NewCache(Options{AllowTTL: time.Hour, DenyTTL: time.Hour})
+	defer c.Close()
+
+	// fnv32a("k64"), fnv32a("k86"), and fnv32a("k110") all land on shard 0 of the default
+	// shardCount=32, so inserting them through the real c.set exercises shard 0's eviction path
+	// end-to-end instead of reimplementing it against the shard directly.
+	keyA, keyB, keyC := "k64", "k86", "k110"
+	s := c.shardFor(keyA)
+	for _, k := range []string{keyB, keyC} {
+		if c.shardFor(k) != s {
+			t.Fatalf("test setup bug: %q does not hash to the same shard as %q", k, keyA)
+		}
+	}
+
+	s.mu.Lock()
+	s.maxSize = 2
+	s.mu.Unlock()
+
+	c.set(keyA, true)
+	c.set(keyB, true)
+	c.set(keyC, true) // evicts keyA unless keyA was most-recently used
+
+	s.mu.Lock()
+	_, hasA := s.items[keyA]
+	_, hasC := s.items[keyC]
+	s.mu.Unlock()
+
+	if hasA {
+		t.Fatalf("expected least-recently-used entry to be evicted")
+	}
+	if !hasC {
+		t.Fatalf("expected most recently inserted entry to survive")
+	}
+}
+
+func TestMaxEntriesBelowShardCountIsHonored(t *testing.T) {
+	const maxEntries = 5
+	c := NewCache(Options{AllowTTL: time.Hour, DenyTTL: time.Hour, MaxEntries: maxEntries})
+	defer c.Close()
+
+	if got := len(c.shards); got > maxEntries {
+		t.Fatalf("expected shard count to shrink to at most %d, got %d", maxEntries, got)
+	}
+
+	for i := 0; i < maxEntries*4; i++ {
+		c.set(fmt.Sprintf("k%d", i), true)
+	}
+
+	var total int
+	for _, s := range c.shards {
+		s.mu.Lock()
+		total += len(s.items)
+		s.mu.Unlock()
+	}
+	if total > maxEntries {
+		t.Fatalf("expected at most %d entries across all shards, got %d", maxEntries, total)
+	}
+}
+
+func TestGetOrLoadCoalescesConcurrentCalls(t *testing.T) {
+	c := NewCache(Options{AllowTTL: time.Minute, DenyTTL: time.Minute})
+	defer c.Close()
+
+	var calls int32
+	load := func(ctx context.Context) (bool, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return true, nil
+	}
+
+	const n = 20
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			v, err := c.GetOrLoad(context.Background(), "shared-key", load)
+			if err != nil || !v {
+				t.Errorf("GetOrLoad: v=%v err=%v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected exactly 1 upstream call, got %d", got)
+	}
+}
+
+func TestGetOrLoadPropagatesError(t *testing.T) {
+	c := NewCache(Options{AllowTTL: time.Minute, DenyTTL: time.Minute})
+	defer c.Close()
+
+	wantErr := fmt.Errorf("upstream boom")
+	_, err := c.GetOrLoad(context.Background(), "k", func(ctx context.Context) (bool, error) {
+		return false, wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("expected error to propagate, got %v", err)
+	}
+
+	// A failed load must not poison the cache.
+	var out bool
+	if found, _ := c.Get("k", &out); found {
+		t.Fatalf("expected no entry to be cached after a failed load")
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package options
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// ARCAuthzMode authorizes SubjectAccessReviews against an Azure Arc connected cluster.
+	ARCAuthzMode = "arc"
+	// AKSAuthzMode authorizes SubjectAccessReviews against an AKS managed cluster.
+	AKSAuthzMode = "aks"
+	// FleetAuthzMode authorizes SubjectAccessReviews against an AKS fleet.
+	FleetAuthzMode = "fleet"
+)
+
+const (
+	defaultCheckAccessTimeout = 5 * time.Second
+	defaultCacheTTLAllow      = 5 * time.Minute
+	defaultCacheTTLDeny       = 30 * time.Second
+	defaultCacheMaxEntries    = 10000
+	// defaultMinConcurrency/defaultMaxConcurrency bound the adaptive limiter so a deployment
+	// that never sets --azure.concurrency-min/-max still fans out sendCheckAccessRequest calls
+	// instead of silently serializing them to one in-flight call.
+	defaultMinConcurrency   = 4
+	defaultMaxConcurrency   = 64
+	defaultBreakerTripRatio = 0.5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// Options configures the azure RBAC authorizer: which cluster type to authorize against, the ARM
+// resource scope, and the caching/concurrency/circuit-breaker behavior of the checkaccess
+// pipeline.
+type Options struct {
+	AuthzMode        string
+	ResourceId       string
+	AKSAuthzTokenURL string
+
+	ARMCallLimit                    int
+	SkipAuthzForNonAADUsers         bool
+	SkipAuthzCheck                  []string
+	AllowNonResDiscoveryPathAccess  bool
+	UseNamespaceResourceScopeFormat bool
+
+	// CheckAccessTimeout bounds a single CheckAccess call (when the caller's context has no
+	// deadline of its own) so a stuck ARM call cannot hold a Kubernetes API request open
+	// indefinitely.
+	CheckAccessTimeout time.Duration
+
+	// CacheTTLAllow and CacheTTLDeny are the result cache's per-decision TTLs; CacheMaxEntries
+	// bounds its total size across all shards. See authz/cache.
+	CacheTTLAllow   time.Duration
+	CacheTTLDeny    time.Duration
+	CacheMaxEntries int
+
+	// MinConcurrency and MaxConcurrency bound the adaptive limiter that caps in-flight
+	// sendCheckAccessRequest goroutines as the ARM read-quota EWMA rises and falls.
+	MinConcurrency int
+	MaxConcurrency int
+
+	// BreakerTripRatio is the rolling 429/5xx ratio that trips the circuit breaker open;
+	// BreakerCooldown is how long it stays open before admitting a half-open probe.
+	BreakerTripRatio float64
+	BreakerCooldown  time.Duration
+}
+
+func NewOptions() Options {
+	return Options{
+		CheckAccessTimeout: defaultCheckAccessTimeout,
+		CacheTTLAllow:      defaultCacheTTLAllow,
+		CacheTTLDeny:       defaultCacheTTLDeny,
+		CacheMaxEntries:    defaultCacheMaxEntries,
+		MinConcurrency:     defaultMinConcurrency,
+		MaxConcurrency:     defaultMaxConcurrency,
+		BreakerTripRatio:   defaultBreakerTripRatio,
+		BreakerCooldown:    defaultBreakerCooldown,
+	}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.AuthzMode, "azure.authz-mode", o.AuthzMode, "Authorization mode: arc, aks, or fleet")
+	fs.StringVar(&o.ResourceId, "azure.resource-id", o.ResourceId, "Azure Resource ID to use for checkaccess calls")
+	fs.StringVar(&o.AKSAuthzTokenURL, "azure.aks-authz-token-url", o.AKSAuthzTokenURL, "URL used to acquire a token for AKS/fleet authorization")
+	fs.IntVar(&o.ARMCallLimit, "azure.arm-call-limit", o.ARMCallLimit, "Configured ARM subscription read-quota threshold used to throttle and adapt concurrency; 0 disables ARM-budget-based throttling")
+	fs.BoolVar(&o.SkipAuthzForNonAADUsers, "azure.skip-authz-for-non-aad-users", o.SkipAuthzForNonAADUsers, "Skip checkaccess for users that are not backed by AAD")
+	fs.StringSliceVar(&o.SkipAuthzCheck, "azure.skip-authz-check", o.SkipAuthzCheck, "List of usernames to skip checkaccess for")
+	fs.BoolVar(&o.AllowNonResDiscoveryPathAccess, "azure.allow-nonres-discovery-path-access", o.AllowNonResDiscoveryPathAccess, "Allow unauthenticated access to non-resource discovery paths (/api, /openapi, /version, /healthz)")
+	fs.BoolVar(&o.UseNamespaceResourceScopeFormat, "azure.use-namespace-resource-scope-format", o.UseNamespaceResourceScopeFormat, "Use the namespace resource scope format when building the checkaccess request")
+	fs.DurationVar(&o.CheckAccessTimeout, "azure.checkaccess-timeout", o.CheckAccessTimeout, "Per-request deadline for a single CheckAccess call when the caller's context has no deadline of its own")
+	fs.DurationVar(&o.CacheTTLAllow, "azure.cache-ttl-allow", o.CacheTTLAllow, "How long an allowed checkaccess decision stays cached")
+	fs.DurationVar(&o.CacheTTLDeny, "azure.cache-ttl-deny", o.CacheTTLDeny, "How long a denied checkaccess decision stays cached")
+	fs.IntVar(&o.CacheMaxEntries, "azure.cache-max-entries", o.CacheMaxEntries, "Maximum number of entries held in the checkaccess result cache across all shards; 0 disables the cap")
+	fs.IntVar(&o.MinConcurrency, "azure.concurrency-min", o.MinConcurrency, "Floor on the number of in-flight checkaccess requests the adaptive limiter will shrink to")
+	fs.IntVar(&o.MaxConcurrency, "azure.concurrency-max", o.MaxConcurrency, "Ceiling on the number of in-flight checkaccess requests the adaptive limiter will grow to")
+	fs.Float64Var(&o.BreakerTripRatio, "azure.breaker-trip-ratio", o.BreakerTripRatio, "Rolling 429/5xx response ratio that trips the checkaccess circuit breaker open")
+	fs.DurationVar(&o.BreakerCooldown, "azure.breaker-cooldown", o.BreakerCooldown, "How long the checkaccess circuit breaker stays open before admitting a half-open probe")
+}
@@ -0,0 +1,235 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rbac
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// adaptiveLimiter is a resizable counting semaphore: the number of concurrent holders allowed
+// can be grown or shrunk at any time via setLimit, unlike golang.org/x/sync/semaphore.Weighted
+// whose capacity is fixed at construction.
+type adaptiveLimiter struct {
+	mu       sync.Mutex
+	waitCh   chan struct{}
+	inFlight int32
+	limit    int32
+	minLimit int32
+	maxLimit int32
+}
+
+func newAdaptiveLimiter(minLimit, maxLimit int32) *adaptiveLimiter {
+	if minLimit < 1 {
+		minLimit = 1
+	}
+	if maxLimit < minLimit {
+		maxLimit = minLimit
+	}
+	return &adaptiveLimiter{
+		waitCh:   make(chan struct{}),
+		limit:    maxLimit,
+		minLimit: minLimit,
+		maxLimit: maxLimit,
+	}
+}
+
+// acquire blocks until a slot is free or ctx is done.
+func (l *adaptiveLimiter) acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inFlight < l.limit {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		wait := l.waitCh
+		l.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release frees a slot acquired via acquire.
+func (l *adaptiveLimiter) release() {
+	l.mu.Lock()
+	l.inFlight--
+	l.mu.Unlock()
+	l.wake()
+}
+
+// setLimit resizes the semaphore, clamped to [minLimit, maxLimit].
+func (l *adaptiveLimiter) setLimit(n int32) {
+	if n < l.minLimit {
+		n = l.minLimit
+	}
+	if n > l.maxLimit {
+		n = l.maxLimit
+	}
+
+	l.mu.Lock()
+	changed := n != l.limit
+	l.limit = n
+	l.mu.Unlock()
+
+	if changed {
+		l.wake()
+	}
+}
+
+func (l *adaptiveLimiter) currentLimit() int32 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// wake releases every goroutine currently blocked in acquire so they can re-check the limit.
+func (l *adaptiveLimiter) wake() {
+	l.mu.Lock()
+	ch := l.waitCh
+	l.waitCh = make(chan struct{})
+	l.mu.Unlock()
+	close(ch)
+}
+
+type breakerState int32
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker trips closed->open once the EWMA of 429/5xx responses crosses tripRatio, then
+// after cooldown lets exactly one probe request through (half-open); a successful probe closes
+// it again, a failed one re-opens it.
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	state       breakerState
+	openedAt    time.Time
+	errorRatio  float64
+	probeActive bool
+
+	tripRatio float64
+	cooldown  time.Duration
+}
+
+func newCircuitBreaker(tripRatio float64, cooldown time.Duration) *circuitBreaker {
+	if tripRatio <= 0 {
+		tripRatio = 0.5
+	}
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	return &circuitBreaker{tripRatio: tripRatio, cooldown: cooldown}
+}
+
+// allow reports whether a new call may proceed, transitioning open->half-open once cooldown has
+// elapsed and admitting exactly one probe at a time while half-open.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerClosed:
+		return true
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probeActive = true
+		return true
+	case breakerHalfOpen:
+		if b.probeActive {
+			return false
+		}
+		b.probeActive = true
+		return true
+	default:
+		return true
+	}
+}
+
+const breakerErrorRatioDecay = 0.2
+
+// recordResult folds the outcome of one upstream call into the rolling error ratio and advances
+// the breaker's state machine.
+func (b *circuitBreaker) recordResult(isError bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sample := 0.0
+	if isError {
+		sample = 1.0
+	}
+	b.errorRatio = breakerErrorRatioDecay*sample + (1-breakerErrorRatioDecay)*b.errorRatio
+	breakerErrorRatioGauge.Set(b.errorRatio)
+
+	switch b.state {
+	case breakerHalfOpen:
+		b.probeActive = false
+		if isError {
+			b.state = breakerOpen
+			b.openedAt = time.Now()
+		} else {
+			b.state = breakerClosed
+			b.errorRatio = 0
+		}
+	case breakerClosed:
+		if b.errorRatio >= b.tripRatio {
+			b.state = breakerOpen
+			b.openedAt = time.Now()
+		}
+	}
+	breakerStateGauge.Set(float64(b.state))
+}
+
+func isThrottleOrServerError(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+var (
+	concurrencyLimitGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "guard_azure_checkaccess_concurrency_limit",
+		Help: "Current adaptive cap on in-flight checkaccess requests.",
+	})
+
+	breakerStateGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "guard_azure_checkaccess_breaker_state",
+		Help: "Circuit breaker state: 0=closed, 1=open, 2=half-open.",
+	})
+
+	breakerErrorRatioGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "guard_azure_checkaccess_breaker_error_ratio",
+		Help: "Rolling EWMA of the 429/5xx response ratio driving the circuit breaker.",
+	})
+
+	armRemainingGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "guard_azure_checkaccess_arm_remaining_ewma",
+		Help: "EWMA of the x-ms-ratelimit-remaining-subscription-reads header across responses.",
+	})
+)
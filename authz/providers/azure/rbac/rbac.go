@@ -17,11 +17,13 @@ package rbac
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"strconv"
 	"strings"
@@ -30,7 +32,7 @@ import (
 
 	auth "go.kubeguard.dev/guard/auth/providers/azure"
 	"go.kubeguard.dev/guard/auth/providers/azure/graph"
-	"go.kubeguard.dev/guard/authz"
+	"go.kubeguard.dev/guard/authz/cache"
 	authzOpts "go.kubeguard.dev/guard/authz/providers/azure/options"
 	azureutils "go.kubeguard.dev/guard/util/azure"
 	errutils "go.kubeguard.dev/guard/util/error"
@@ -39,6 +41,11 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	v "gomodules.xyz/x/version"
 	authzv1 "k8s.io/api/authorization/v1"
 	"k8s.io/klog/v2"
@@ -51,9 +58,14 @@ const (
 	checkAccessPath           = "/providers/Microsoft.Authorization/checkaccess"
 	checkAccessAPIVersion     = "2018-09-01-preview"
 	remainingSubReadARMHeader = "x-ms-ratelimit-remaining-subscription-reads"
+	armRequestIDHeader        = "x-ms-request-id"
 	expiryDelta               = 60 * time.Second
 )
 
+// tracer emits spans for the RBAC checkaccess pipeline. The actual exporter (OTLP, stdout, or
+// none) is selected at process startup via OTEL_EXPORTER_* env vars; see commands.NewCmdRun.
+var tracer = otel.Tracer("guard/authz/azure")
+
 type AuthzInfo struct {
 	AADEndpoint string
 	ARMEndPoint string
@@ -84,6 +96,21 @@ type AccessInfo struct {
 	useNamespaceResourceScopeFormat bool
 	lock                            sync.RWMutex
 	operationsMap                   azureutils.OperationsMap
+	// checkAccessTimeout bounds a single CheckAccess call (via --azure.checkaccess-timeout) when
+	// the caller's context has no deadline of its own, so a stuck ARM call can't hold a
+	// Kubernetes API request open indefinitely.
+	checkAccessTimeout time.Duration
+	resultCache        *cache.Cache
+
+	// limiter caps in-flight sendCheckAccessRequest goroutines, shrinking as the ARM read-quota
+	// EWMA drops toward armCallLimit and growing back up to maxConcurrency as it recovers.
+	limiter *adaptiveLimiter
+	// breaker fast-fails (or safe-default-denies) new CheckAccess calls once the rolling
+	// 429/5xx ratio trips, until a half-open probe succeeds.
+	breaker *circuitBreaker
+
+	armRemainingMu   sync.Mutex
+	armRemainingEWMA float64
 }
 
 var (
@@ -154,6 +181,14 @@ func newAccessInfo(tokenProvider graph.TokenProvider, rbacURL *url.URL, opts aut
 		skipAuthzForNonAADUsers:         opts.SkipAuthzForNonAADUsers,
 		allowNonResDiscoveryPathAccess:  opts.AllowNonResDiscoveryPathAccess,
 		useNamespaceResourceScopeFormat: opts.UseNamespaceResourceScopeFormat,
+		checkAccessTimeout:              opts.CheckAccessTimeout,
+		resultCache: cache.NewCache(cache.Options{
+			AllowTTL:   opts.CacheTTLAllow,
+			DenyTTL:    opts.CacheTTLDeny,
+			MaxEntries: opts.CacheMaxEntries,
+		}),
+		limiter: newAdaptiveLimiter(int32(opts.MinConcurrency), int32(opts.MaxConcurrency)),
+		breaker: newCircuitBreaker(opts.BreakerTripRatio, opts.BreakerCooldown),
 	}
 
 	u.skipCheck = make(map[string]void, len(opts.SkipAuthzCheck))
@@ -177,26 +212,104 @@ func New(opts authzOpts.Options, authopts auth.Options, authzInfo *AuthzInfo, op
 		return nil, err
 	}
 
-	var tokenProvider graph.TokenProvider
+	tokenProvider, err := newTokenProvider(opts, authopts, authzInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	accessInfo, err := newAccessInfo(tokenProvider, rbacURL, opts, operationsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	if authopts.KeyVaultURI != "" {
+		kv, err := auth.NewKeyVaultSecretSource(authopts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to initialize key vault secret source")
+		}
+		go kv.Watch(context.Background(), accessInfo.rotateCredential(opts, authopts, authzInfo))
+	}
+
+	return accessInfo, nil
+}
+
+// rotateCredential returns a KeyVaultSecretSource.Watch callback that rebuilds the token
+// provider from freshly-fetched secret/certificate material and atomically swaps it in under
+// AccessInfo.lock, so the next RefreshToken exchanges the new material.
+func (a *AccessInfo) rotateCredential(opts authzOpts.Options, authopts auth.Options, authzInfo *AuthzInfo) func(clientSecret, clientCertPEM string) {
+	return func(clientSecret, clientCertPEM string) {
+		rotated := authopts
+		if clientCertPEM != "" {
+			certPath, err := writeTempCert(clientCertPEM)
+			if err != nil {
+				klog.Errorf("failed to persist rotated key vault certificate: %s", err)
+				return
+			}
+			rotated.CertPath = certPath
+			if rotated.AuthMode == "" {
+				rotated.AuthMode = auth.AuthModeCert
+			}
+		}
+		if clientSecret != "" {
+			rotated.ClientSecret = clientSecret
+		}
+
+		tp, err := newTokenProvider(opts, rotated, authzInfo)
+		if err != nil {
+			klog.Errorf("failed to rebuild token provider after key vault rotation: %s", err)
+			return
+		}
+		a.SetTokenProvider(tp)
+	}
+}
+
+// writeTempCert persists a PEM certificate fetched from Key Vault to a private temp file so it
+// can be read back through the same azure.Options.CertPath path used for a disk-mounted cert.
+func writeTempCert(pem string) (string, error) {
+	f, err := os.CreateTemp("", "guard-azure-cert-*.pem")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create temp file for rotated certificate")
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(pem); err != nil {
+		return "", errors.Wrap(err, "failed to write rotated certificate")
+	}
+	return f.Name(), nil
+}
+
+// newTokenProvider picks the credential source for ARM token acquisition. Setting
+// --azure.auth-mode to chained, msi, workload-identity or cert routes through the azidentity
+// credential chain (managed identity, workload identity, client secret/certificate) so Guard can
+// run without AZURE_CLIENT_SECRET; leaving it unset or setting it to secret preserves the legacy
+// per-cluster-type providers for existing deployments.
+func newTokenProvider(opts authzOpts.Options, authopts auth.Options, authzInfo *AuthzInfo) (graph.TokenProvider, error) {
+	switch authopts.AuthMode {
+	case auth.AuthModeChained, auth.AuthModeMSI, auth.AuthModeWorkloadIdentity, auth.AuthModeCert:
+		return authopts.NewTokenProvider(authzInfo.ARMEndPoint)
+	}
+
 	switch opts.AuthzMode {
 	case authzOpts.ARCAuthzMode:
-		tokenProvider = graph.NewClientCredentialTokenProvider(authopts.ClientID, authopts.ClientSecret,
+		return graph.NewClientCredentialTokenProvider(authopts.ClientID, authopts.ClientSecret,
 			fmt.Sprintf("%s%s/oauth2/v2.0/token", authzInfo.AADEndpoint, authopts.TenantID),
-			fmt.Sprintf("%s.default", authzInfo.ARMEndPoint))
-	case authzOpts.FleetAuthzMode:
-		tokenProvider = graph.NewAKSTokenProvider(opts.AKSAuthzTokenURL, authopts.TenantID)
-	case authzOpts.AKSAuthzMode:
-		tokenProvider = graph.NewAKSTokenProvider(opts.AKSAuthzTokenURL, authopts.TenantID)
+			fmt.Sprintf("%s.default", authzInfo.ARMEndPoint)), nil
+	case authzOpts.FleetAuthzMode, authzOpts.AKSAuthzMode:
+		return graph.NewAKSTokenProvider(opts.AKSAuthzTokenURL, authopts.TenantID), nil
+	default:
+		return nil, errors.Errorf("unsupported authorization mode %q", opts.AuthzMode)
 	}
-
-	return newAccessInfo(tokenProvider, rbacURL, opts, operationsMap)
 }
 
-func (a *AccessInfo) RefreshToken() error {
+func (a *AccessInfo) RefreshToken(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	a.lock.Lock()
 	defer a.lock.Unlock()
 	if a.IsTokenExpired() {
-		resp, err := a.tokenProvider.Acquire("")
+		resp, err := a.tokenProvider.Acquire(ctx, "")
 		if err != nil {
 			klog.Errorf("%s failed to refresh token : %s", a.tokenProvider.Name(), err.Error())
 			return errors.Wrap(err, "failed to refresh rbac token")
@@ -212,6 +325,53 @@ func (a *AccessInfo) RefreshToken() error {
 	return nil
 }
 
+// SetTokenProvider atomically swaps the credential used for future RefreshToken calls under
+// AccessInfo.lock and forces the next call to exchange fresh material immediately. Used by the
+// Key Vault secret source to pick up a rotated client secret/certificate without a pod restart.
+func (a *AccessInfo) SetTokenProvider(tp graph.TokenProvider) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.tokenProvider = tp
+	a.expiresAt = time.Time{}
+}
+
+// updateConcurrencyFromRemaining folds the latest x-ms-ratelimit-remaining-subscription-reads
+// value into an EWMA and rescales the adaptive limiter between its min and max bounds: at or
+// below armCallLimit the limiter shrinks to its floor, at 2x armCallLimit or above it grows to
+// its ceiling, scaling linearly in between.
+func (a *AccessInfo) updateConcurrencyFromRemaining(remaining int) {
+	a.armRemainingMu.Lock()
+	const decay = 0.3
+	if a.armRemainingEWMA == 0 {
+		a.armRemainingEWMA = float64(remaining)
+	} else {
+		a.armRemainingEWMA = decay*float64(remaining) + (1-decay)*a.armRemainingEWMA
+	}
+	ewma := a.armRemainingEWMA
+	a.armRemainingMu.Unlock()
+
+	armRemainingGauge.Set(ewma)
+
+	min, max := a.limiter.minLimit, a.limiter.maxLimit
+	// armCallLimit<=0 means no ARM read-quota threshold was configured at all, so there is
+	// nothing to scale down from: stay at the ceiling instead of falling through to min, which
+	// would otherwise pin every unconfigured deployment's fan-out at minLimit forever.
+	newLimit := max
+	if headroom := float64(a.armCallLimit); headroom > 0 {
+		newLimit = min
+		if ewma > headroom {
+			frac := (ewma - headroom) / headroom
+			if frac > 1 {
+				frac = 1
+			}
+			newLimit = min + int32(frac*float64(max-min))
+		}
+	}
+
+	a.limiter.setLimit(newLimit)
+	concurrencyLimitGauge.Set(float64(a.limiter.currentLimit()))
+}
+
 func (a *AccessInfo) IsTokenExpired() bool {
 	return a.expiresAt.Before(time.Now())
 }
@@ -220,11 +380,11 @@ func (a *AccessInfo) ShouldSkipAuthzCheckForNonAADUsers() bool {
 	return a.skipAuthzForNonAADUsers
 }
 
-func (a *AccessInfo) GetResultFromCache(request *authzv1.SubjectAccessReviewSpec, store authz.Store) (bool, bool) {
+func (a *AccessInfo) GetResultFromCache(request *authzv1.SubjectAccessReviewSpec) (bool, bool) {
 	var result bool
 	key := getResultCacheKey(request)
 	klog.V(10).Infof("Cache search for key: %s", key)
-	found, _ := store.Get(key, &result)
+	found, _ := a.resultCache.Get(key, &result)
 
 	if found {
 		if result {
@@ -237,6 +397,28 @@ func (a *AccessInfo) GetResultFromCache(request *authzv1.SubjectAccessReviewSpec
 	return found, result
 }
 
+// CheckAccessCached resolves request through the result cache, coalescing concurrent lookups
+// for the same key into a single upstream CheckAccess call via the cache's singleflight group.
+func (a *AccessInfo) CheckAccessCached(ctx context.Context, request *authzv1.SubjectAccessReviewSpec) (*authzv1.SubjectAccessReviewStatus, error) {
+	key := getResultCacheKey(request)
+
+	allowed, err := a.resultCache.GetOrLoad(ctx, key, func(ctx context.Context) (bool, error) {
+		status, err := a.CheckAccess(ctx, request)
+		if err != nil {
+			return false, err
+		}
+		return !status.Denied, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if allowed {
+		return &authzv1.SubjectAccessReviewStatus{Allowed: true}, nil
+	}
+	return &authzv1.SubjectAccessReviewStatus{Denied: true}, nil
+}
+
 func (a *AccessInfo) SkipAuthzCheck(request *authzv1.SubjectAccessReviewSpec) bool {
 	if a.clusterType == connectedClusters {
 		_, ok := a.skipCheck[strings.ToLower(request.User)]
@@ -245,10 +427,10 @@ func (a *AccessInfo) SkipAuthzCheck(request *authzv1.SubjectAccessReviewSpec) bo
 	return false
 }
 
-func (a *AccessInfo) SetResultInCache(request *authzv1.SubjectAccessReviewSpec, result bool, store authz.Store) error {
+func (a *AccessInfo) SetResultInCache(request *authzv1.SubjectAccessReviewSpec, result bool) error {
 	key := getResultCacheKey(request)
 	klog.V(5).Infof("Cache set for key: %s, value: %t", key, result)
-	return store.Set(key, result)
+	return a.resultCache.Set(key, result)
 }
 
 func (a *AccessInfo) AllowNonResPathDiscoveryAccess(request *authzv1.SubjectAccessReviewSpec) bool {
@@ -274,9 +456,72 @@ func (a *AccessInfo) setReqHeaders(req *http.Request) {
 	}
 }
 
-func (a *AccessInfo) CheckAccess(request *authzv1.SubjectAccessReviewSpec) (*authzv1.SubjectAccessReviewStatus, error) {
+// RequestNamespace, RequestVerb, and RequestResource pull the span attribute values out of
+// whichever of ResourceAttributes/NonResourceAttributes is populated on the request. Exported so
+// the SubjectAccessReview HTTP handler in server can attach the same attributes to the root span
+// it starts before calling CheckAccess.
+func RequestNamespace(request *authzv1.SubjectAccessReviewSpec) string {
+	if request.ResourceAttributes != nil {
+		return request.ResourceAttributes.Namespace
+	}
+	return ""
+}
+
+func RequestVerb(request *authzv1.SubjectAccessReviewSpec) string {
+	if request.ResourceAttributes != nil {
+		return request.ResourceAttributes.Verb
+	}
+	if request.NonResourceAttributes != nil {
+		return request.NonResourceAttributes.Verb
+	}
+	return ""
+}
+
+func RequestResource(request *authzv1.SubjectAccessReviewSpec) string {
+	if request.ResourceAttributes != nil {
+		return request.ResourceAttributes.Resource
+	}
+	if request.NonResourceAttributes != nil {
+		return request.NonResourceAttributes.Path
+	}
+	return ""
+}
+
+// ClusterType reports the ARM resource provider namespace (managedClusters, fleets, or
+// connectedClusters) this AccessInfo authorizes against, for callers that want to attach it as a
+// span or log attribute outside this package.
+func (a *AccessInfo) ClusterType() string {
+	return a.clusterType
+}
+
+// CheckAccess performs the checkaccess fan-out for request. Its span is a child of whatever span
+// is already active on ctx: the SubjectAccessReview HTTP handler in server is expected to have
+// started the root span carrying the authz.* attributes before calling in, so they aren't
+// duplicated here.
+func (a *AccessInfo) CheckAccess(ctx context.Context, request *authzv1.SubjectAccessReviewSpec) (*authzv1.SubjectAccessReviewStatus, error) {
+	if a.checkAccessTimeout > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, a.checkAccessTimeout)
+			defer cancel()
+		}
+	}
+
+	ctx, span := tracer.Start(ctx, "CheckAccess")
+	defer span.End()
+
+	if !a.breaker.allow() {
+		span.SetStatus(codes.Error, "circuit breaker open")
+		if a.allowNonResDiscoveryPathAccess {
+			return &authzv1.SubjectAccessReviewStatus{Denied: true, Reason: "azure rbac circuit breaker is open, denying by default"}, nil
+		}
+		return nil, errutils.WithCode(errors.New("azure rbac circuit breaker is open"), http.StatusServiceUnavailable)
+	}
+
 	checkAccessBodies, err := prepareCheckAccessRequestBody(request, a.clusterType, a.operationsMap, a.azureResourceId, a.useNamespaceResourceScopeFormat)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, errors.Wrap(err, "error in preparing check access request")
 	}
 
@@ -293,7 +538,10 @@ func (a *AccessInfo) CheckAccess(request *authzv1.SubjectAccessReviewSpec) (*aut
 	params.Add("api-version", checkAccessAPIVersion)
 	checkAccessURL.RawQuery = params.Encode()
 
-	var wg sync.WaitGroup // New wait group
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
 
 	ch := make(chan reviewResult, len(checkAccessBodies))
 	if len(checkAccessBodies) > 1 {
@@ -301,7 +549,15 @@ func (a *AccessInfo) CheckAccess(request *authzv1.SubjectAccessReviewSpec) (*aut
 	}
 	for _, checkAccessBody := range checkAccessBodies {
 		wg.Add(1)
-		go a.sendCheckAccessRequest(checkAccessURL, checkAccessBody, &wg, ch)
+		go func(body *CheckAccessRequest) {
+			defer wg.Done()
+			if err := a.limiter.acquire(ctx); err != nil {
+				ch <- reviewResult{err: errutils.WithCode(err, http.StatusGatewayTimeout)}
+				return
+			}
+			defer a.limiter.release()
+			a.sendCheckAccessRequest(ctx, checkAccessURL, body, ch)
+		}(checkAccessBody)
 	}
 
 	go func() {
@@ -310,28 +566,47 @@ func (a *AccessInfo) CheckAccess(request *authzv1.SubjectAccessReviewSpec) (*aut
 	}()
 
 	var finalResult *authzv1.SubjectAccessReviewStatus
-	for result := range ch {
-		if result.err != nil {
-			return nil, result.err
-		}
-
-		if result.status.Denied {
+	for {
+		select {
+		case <-ctx.Done():
+			// Cancel the in-flight HTTP calls and drain the remaining results in the
+			// background so the goroutines above never block writing to ch.
+			cancel()
+			go func() {
+				for range ch {
+				}
+			}()
+			span.RecordError(ctx.Err())
+			span.SetStatus(codes.Error, ctx.Err().Error())
+			return nil, errutils.WithCode(ctx.Err(), http.StatusGatewayTimeout)
+		case result, ok := <-ch:
+			if !ok {
+				return finalResult, nil
+			}
+			if result.err != nil {
+				span.RecordError(result.err)
+				span.SetStatus(codes.Error, result.err.Error())
+				return nil, result.err
+			}
 			finalResult = result.status
-			break
+			if result.status.Denied {
+				return finalResult, nil
+			}
 		}
-
-		finalResult = result.status
 	}
-
-	return finalResult, nil
 }
 
-func (a *AccessInfo) sendCheckAccessRequest(ctx context.Context, checkAccessURL url.URL, checkAccessBody *CheckAccessRequest, ch chan reviewResult) error {
-	//defer wg.Done()
+func (a *AccessInfo) sendCheckAccessRequest(ctx context.Context, checkAccessURL url.URL, checkAccessBody *CheckAccessRequest, ch chan reviewResult) {
+	ctx, span := tracer.Start(ctx, "sendCheckAccessRequest", trace.WithAttributes(
+		attribute.String("http.url", checkAccessURL.String()),
+	))
+	defer span.End()
+
 	reviewResult := reviewResult{}
 	buf := new(bytes.Buffer)
 	if err := json.NewEncoder(buf).Encode(checkAccessBody); err != nil {
 		reviewResult.err = errutils.WithCode(errors.Wrap(err, "error encoding check access request"), http.StatusInternalServerError)
+		span.RecordError(reviewResult.err)
 		ch <- reviewResult
 		return
 	}
@@ -342,34 +617,57 @@ func (a *AccessInfo) sendCheckAccessRequest(ctx context.Context, checkAccessURL
 		klog.V(10).Infof("binary data:%s", binaryData)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, checkAccessURL.String(), buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, checkAccessURL.String(), buf)
 	if err != nil {
 		reviewResult.err = errutils.WithCode(errors.Wrap(err, "error creating check access request"), http.StatusInternalServerError)
+		span.RecordError(reviewResult.err)
 		ch <- reviewResult
 		return
 	}
 
 	a.setReqHeaders(req)
+	// Propagate the trace context so this hop shows up end-to-end alongside the ARM side of the
+	// call in whatever backend OTEL_EXPORTER_* points at.
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
 	// start time to calculate checkaccess duration
 	start := time.Now()
 	resp, err := a.client.Do(req)
-	duration := time.Since(begin).Seconds()
+	duration := time.Since(start).Seconds()
 	if err != nil {
-		reviewResult.err = errutils.WithCode(errors.Wrap(err, "error in check access request execution"), http.StatusInternalServerError)
-		checkAccessTotal.WithLabelValues(http.StatusInternalServerError).Inc()
-		checkAccessDuration.WithLabelValues(http.StatusInternalServerError).Observe(duration)
+		code := http.StatusInternalServerError
+		if ctx.Err() != nil {
+			code = http.StatusGatewayTimeout
+		}
+		reviewResult.err = errutils.WithCode(errors.Wrap(err, "error in check access request execution"), code)
+		checkAccessTotal.WithLabelValues(strconv.Itoa(code)).Inc()
+		checkAccessDuration.WithLabelValues(strconv.Itoa(code)).Observe(duration)
+		span.RecordError(reviewResult.err)
+		span.SetStatus(codes.Error, reviewResult.err.Error())
 		ch <- reviewResult
 		return
 	}
 
 	defer resp.Body.Close()
 
-	checkAccessTotal.WithLabelValues(resp.StatusCode).Inc()
-	checkAccessDuration.WithLabelValues(resp.StatusCode).Observe(duration)
+	statusCode := strconv.Itoa(resp.StatusCode)
+	checkAccessTotal.WithLabelValues(statusCode).Inc()
+	checkAccessDuration.WithLabelValues(statusCode).Observe(duration)
+	a.breaker.recordResult(isThrottleOrServerError(resp.StatusCode))
+
+	span.SetAttributes(
+		attribute.Int("http.status_code", resp.StatusCode),
+		attribute.String("azure.request_id", resp.Header.Get(armRequestIDHeader)),
+	)
+	if remaining, err := strconv.Atoi(resp.Header.Get(remainingSubReadARMHeader)); err == nil {
+		span.SetAttributes(attribute.Int("azure.arm_remaining", remaining))
+		a.updateConcurrencyFromRemaining(remaining)
+	}
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		reviewResult.err = errutils.WithCode(errors.Wrap(err, "error in reading response body"), http.StatusInternalServerError)
+		span.RecordError(reviewResult.err)
 		ch <- reviewResult
 		return
 	}
@@ -385,10 +683,12 @@ func (a *AccessInfo) sendCheckAccessRequest(ctx context.Context, checkAccessURL
 				checkAccessThrottled.Inc()
 			}
 
-			checkAccessFailed.WithLabelValues(resp.StatusCode).Inc()
+			checkAccessFailed.WithLabelValues(statusCode).Inc()
 		}
 
 		reviewResult.err = errutils.WithCode(errors.Errorf("request %s failed with status code: %d and response: %s", req.URL.Path, resp.StatusCode, string(data)), resp.StatusCode)
+		span.RecordError(reviewResult.err)
+		span.SetStatus(codes.Error, reviewResult.err.Error())
 		ch <- reviewResult
 		return
 	} else {
@@ -411,9 +711,8 @@ func (a *AccessInfo) sendCheckAccessRequest(ctx context.Context, checkAccessURL
 	reviewResult.status, reviewResult.err = ConvertCheckAccessResponse(data)
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return
 	case ch <- reviewResult:
-		return nil
-		// do nothing
+		return
 	}
 }
@@ -17,9 +17,15 @@ limitations under the License.
 package commands
 
 import (
+	"context"
+
 	"go.kubeguard.dev/guard/server"
 
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/exporters/autoexport"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"gomodules.xyz/flags"
 	"k8s.io/klog/v2"
 )
@@ -42,9 +48,32 @@ func NewCmdRun() *cobra.Command {
 			if !srv.AuthRecommendedOptions.SecureServing.UseTLS() {
 				klog.Fatalln("Guard server must use SSL.")
 			}
+
+			shutdownTracing, err := initTracing(cmd.Context())
+			if err != nil {
+				klog.Fatalf("failed to initialize OpenTelemetry tracing: %s", err)
+			}
+			defer shutdownTracing(cmd.Context())
+
 			srv.ListenAndServe()
 		},
 	}
 	srv.AddFlags(cmd.Flags())
 	return cmd
 }
+
+// initTracing selects a span exporter purely from the standard OTEL_EXPORTER_* environment
+// variables (OTLP, stdout, or none), so operators can turn tracing on or repoint it at a
+// different collector without a rebuild. The returned func flushes and closes the exporter.
+func initTracing(ctx context.Context) (func(context.Context) error, error) {
+	exporter, err := autoexport.NewSpanExporter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tp.Shutdown, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package server
+
+import (
+	authzOpts "go.kubeguard.dev/guard/authz/providers/azure/options"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	defaultBindAddress = "0.0.0.0"
+	defaultBindPort    = 8443
+)
+
+// SecureServingOptions configures the HTTPS listener Guard's webhook endpoints are served on.
+type SecureServingOptions struct {
+	BindAddress string
+	BindPort    int
+	// CertFile and KeyFile are the TLS certificate/key pair Guard serves with. Both must be set;
+	// Guard's webhook endpoints must never be served over plain HTTP.
+	CertFile string
+	KeyFile  string
+}
+
+func NewSecureServingOptions() *SecureServingOptions {
+	return &SecureServingOptions{
+		BindAddress: defaultBindAddress,
+		BindPort:    defaultBindPort,
+	}
+}
+
+func (o *SecureServingOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.BindAddress, "secure-addr", o.BindAddress, "Address to bind the secure server to")
+	fs.IntVar(&o.BindPort, "secure-port", o.BindPort, "Port to bind the secure server to")
+	fs.StringVar(&o.CertFile, "tls-cert-file", o.CertFile, "Path to the TLS certificate used to serve HTTPS")
+	fs.StringVar(&o.KeyFile, "tls-private-key-file", o.KeyFile, "Path to the TLS private key used to serve HTTPS")
+}
+
+// UseTLS reports whether both halves of a certificate/key pair have been configured.
+func (o *SecureServingOptions) UseTLS() bool {
+	return o.CertFile != "" && o.KeyFile != ""
+}
+
+// AuthRecommendedOptions groups the options that control how Guard's HTTP server listens.
+type AuthRecommendedOptions struct {
+	SecureServing *SecureServingOptions
+}
+
+func NewAuthRecommendedOptions() *AuthRecommendedOptions {
+	return &AuthRecommendedOptions{SecureServing: NewSecureServingOptions()}
+}
+
+func (o *AuthRecommendedOptions) AddFlags(fs *pflag.FlagSet) {
+	o.SecureServing.AddFlags(fs)
+}
+
+// AuthzRecommendedOptions groups the options that control the azure RBAC authorizer backing the
+// SubjectAccessReview endpoint.
+type AuthzRecommendedOptions struct {
+	Azure authzOpts.Options
+}
+
+func NewAuthzRecommendedOptions() *AuthzRecommendedOptions {
+	return &AuthzRecommendedOptions{Azure: authzOpts.NewOptions()}
+}
+
+func (o *AuthzRecommendedOptions) AddFlags(fs *pflag.FlagSet) {
+	o.Azure.AddFlags(fs)
+}
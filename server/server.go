@@ -0,0 +1,74 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/pflag"
+	authzv1 "k8s.io/api/authorization/v1"
+	"k8s.io/klog/v2"
+)
+
+// subjectAccessReviewPath is the endpoint a kube-apiserver's --authorization-webhook-config-file
+// posts SubjectAccessReviews to.
+const subjectAccessReviewPath = "/apis/authorization.k8s.io/v1/subjectaccessreviews"
+
+// accessInfo is the subset of *rbac.AccessInfo the SubjectAccessReview handler needs. Narrowing
+// it to an interface lets tests exercise the handler, and the mux it is registered on, against a
+// fake instead of a fully-constructed AccessInfo and its ARM/cache/limiter dependencies.
+type accessInfo interface {
+	ClusterType() string
+	SkipAuthzCheck(request *authzv1.SubjectAccessReviewSpec) bool
+	RefreshToken(ctx context.Context) error
+	CheckAccessCached(ctx context.Context, request *authzv1.SubjectAccessReviewSpec) (*authzv1.SubjectAccessReviewStatus, error)
+}
+
+// Server owns Guard's HTTPS listener and routes its webhook endpoints to their handlers.
+type Server struct {
+	AuthRecommendedOptions  *AuthRecommendedOptions
+	AuthzRecommendedOptions *AuthzRecommendedOptions
+
+	// AccessInfo backs the SubjectAccessReview endpoint. Set once the azure RBAC authorizer has
+	// been constructed from AuthzRecommendedOptions, before ListenAndServe is called.
+	AccessInfo accessInfo
+}
+
+func (s *Server) AddFlags(fs *pflag.FlagSet) {
+	s.AuthRecommendedOptions.AddFlags(fs)
+	s.AuthzRecommendedOptions.AddFlags(fs)
+}
+
+// Handler builds the mux Guard serves: the SubjectAccessReview webhook, registered at the path
+// the kube-apiserver's webhook authorizer posts to.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(subjectAccessReviewPath, &SubjectAccessReviewHandler{
+		AccessInfo:         s.AccessInfo,
+		CheckAccessTimeout: s.AuthzRecommendedOptions.Azure.CheckAccessTimeout,
+	})
+	return mux
+}
+
+// ListenAndServe serves Handler() over HTTPS using the configured certificate/key pair.
+func (s *Server) ListenAndServe() {
+	addr := fmt.Sprintf("%s:%d", s.AuthRecommendedOptions.SecureServing.BindAddress, s.AuthRecommendedOptions.SecureServing.BindPort)
+	klog.Infof("guard server listening on %s", addr)
+	err := http.ListenAndServeTLS(addr, s.AuthRecommendedOptions.SecureServing.CertFile, s.AuthRecommendedOptions.SecureServing.KeyFile, s.Handler())
+	klog.Fatalf("guard server exited: %s", err)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright The Guard Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authzv1 "k8s.io/api/authorization/v1"
+)
+
+// fakeAccessInfo is a minimal accessInfo stand-in so Server.Handler can be tested end-to-end
+// through its real mux without constructing a full rbac.AccessInfo and its ARM dependencies.
+type fakeAccessInfo struct {
+	allowed bool
+}
+
+func (f *fakeAccessInfo) ClusterType() string { return "aks" }
+
+func (f *fakeAccessInfo) SkipAuthzCheck(*authzv1.SubjectAccessReviewSpec) bool { return false }
+
+func (f *fakeAccessInfo) RefreshToken(ctx context.Context) error { return nil }
+
+func (f *fakeAccessInfo) CheckAccessCached(ctx context.Context, request *authzv1.SubjectAccessReviewSpec) (*authzv1.SubjectAccessReviewStatus, error) {
+	return &authzv1.SubjectAccessReviewStatus{Allowed: f.allowed}, nil
+}
+
+func TestServerHandlerRoutesSubjectAccessReview(t *testing.T) {
+	srv := &Server{
+		AuthRecommendedOptions:  NewAuthRecommendedOptions(),
+		AuthzRecommendedOptions: NewAuthzRecommendedOptions(),
+		AccessInfo:              &fakeAccessInfo{allowed: true},
+	}
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	body, err := json.Marshal(authzv1.SubjectAccessReview{
+		Spec: authzv1.SubjectAccessReviewSpec{User: "alice"},
+	})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	resp, err := http.Post(ts.URL+subjectAccessReviewPath, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var sar authzv1.SubjectAccessReview
+	if err := json.NewDecoder(resp.Body).Decode(&sar); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !sar.Status.Allowed {
+		t.Fatalf("expected the real mux to route to SubjectAccessReviewHandler and return Allowed=true")
+	}
+}
+
+func TestServerHandlerRejectsUnroutedPath(t *testing.T) {
+	srv := &Server{
+		AuthRecommendedOptions:  NewAuthRecommendedOptions(),
+		AuthzRecommendedOptions: NewAuthzRecommendedOptions(),
+		AccessInfo:              &fakeAccessInfo{allowed: true},
+	}
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/not-a-registered-path")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status 404 for an unregistered path, got %d", resp.StatusCode)
+	}
+}